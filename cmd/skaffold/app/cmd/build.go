@@ -18,14 +18,24 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"io"
+	"os"
+	"time"
 
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+var (
+	waitForDeployments bool
+	deployTimeout      time.Duration
+	failFast           bool
+	outputFormat       string
+)
+
 // NewCmdBuild describes the CLI command to build artifacts.
 func NewCmdBuild(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
@@ -37,9 +47,21 @@ func NewCmdBuild(out io.Writer) *cobra.Command {
 		},
 	}
 	AddRunDevFlags(cmd)
+	AddWaitFlags(cmd)
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Result format: 'text', 'json', 'yaml' or 'go-template=<template>'")
 	return cmd
 }
 
+// AddWaitFlags adds the --wait, --timeout and --fail-fast flags shared by
+// `build` and `deploy`: once the resources they're responsible for have been
+// applied, these flags make the command block until every one of them is
+// ready.
+func AddWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&waitForDeployments, "wait", false, "Wait for deployed resources to stabilize before returning")
+	cmd.Flags().DurationVar(&deployTimeout, "timeout", 10*time.Minute, "Maximum time to wait for deployed resources to stabilize when --wait is set")
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Abort waiting on all resources as soon as one of them fails, instead of waiting out its own timeout")
+}
+
 func build(out io.Writer, filename string) error {
 	ctx := context.Background()
 
@@ -58,8 +80,25 @@ func build(out io.Writer, filename string) error {
 		return errors.Wrap(err, "build step")
 	}
 
-	for _, build := range bRes {
-		fmt.Fprintln(out, build.ImageName, "->", build.Tag)
+	if err := printBuildResult(out, outputFormat, bRes); err != nil {
+		return errors.Wrap(err, "writing build result")
+	}
+
+	if waitForDeployments {
+		cli := &kubectl.CLI{
+			Namespace:   opts.Namespace,
+			KubeContext: opts.KubeContext,
+		}
+		// runner.RunID() is the same ID the deploy phase stamped its
+		// resources with when runner.NewForConfig created it, so this
+		// invocation's status check only ever discovers what it deployed.
+		//
+		// The summary goes to stderr, not out: out may be carrying
+		// --output json/yaml build results that a caller is parsing as a
+		// single structured document, and the summary would corrupt that.
+		if err := deploy.StatusCheck(ctx, os.Stderr, cli, runner.RunID(), deployTimeout, failFast); err != nil {
+			return errors.Wrap(err, "waiting for resources to stabilize")
+		}
 	}
 
 	return err
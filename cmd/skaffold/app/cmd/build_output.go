@@ -0,0 +1,71 @@
+/*
+Copyright 2019 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// printBuildResult writes the artifacts built by `skaffold build` to out in
+// the requested format, so that CI pipelines and GitOps writers can consume
+// tag+digest pairs without regex-parsing stdout.
+func printBuildResult(out io.Writer, format string, artifacts []build.Artifact) error {
+	switch {
+	case format == "text":
+		for _, a := range artifacts {
+			fmt.Fprintln(out, a.ImageName, "->", a.Tag)
+		}
+		return nil
+
+	case format == "json":
+		enc := json.NewEncoder(out)
+		return enc.Encode(artifacts)
+
+	case format == "yaml":
+		buf, err := yaml.Marshal(artifacts)
+		if err != nil {
+			return errors.Wrap(err, "marshalling artifacts to yaml")
+		}
+		_, err = out.Write(buf)
+		return err
+
+	case strings.HasPrefix(format, "go-template="):
+		tmplText := strings.TrimPrefix(format, "go-template=")
+		tmpl, err := template.New("build-output").Parse(tmplText)
+		if err != nil {
+			return errors.Wrap(err, "parsing template")
+		}
+		for _, a := range artifacts {
+			if err := tmpl.Execute(out, a); err != nil {
+				return errors.Wrap(err, "executing template")
+			}
+			fmt.Fprintln(out)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown output format %q: must be 'text', 'json', 'yaml' or 'go-template=<template>'", format)
+	}
+}
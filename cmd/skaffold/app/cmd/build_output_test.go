@@ -0,0 +1,75 @@
+/*
+Copyright 2019 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestPrintBuildResult(t *testing.T) {
+	artifacts := []build.Artifact{
+		{ImageName: "gcr.io/foo/bar", Tag: "gcr.io/foo/bar:v1", Digest: "sha256:abc"},
+	}
+
+	var tests = []struct {
+		description string
+		format      string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "text",
+			format:      "text",
+			expected:    "gcr.io/foo/bar -> gcr.io/foo/bar:v1\n",
+		},
+		{
+			description: "json",
+			format:      "json",
+			expected:    "[{\"imageName\":\"gcr.io/foo/bar\",\"tag\":\"gcr.io/foo/bar:v1\",\"digest\":\"sha256:abc\",\"duration\":0,\"cacheHit\":false}]\n",
+		},
+		{
+			description: "yaml",
+			format:      "yaml",
+			expected:    "- imageName: gcr.io/foo/bar\n  tag: gcr.io/foo/bar:v1\n  digest: sha256:abc\n  duration: 0s\n  cacheHit: false\n",
+		},
+		{
+			description: "go-template",
+			format:      "go-template={{.ImageName}}={{.Tag}}",
+			expected:    "gcr.io/foo/bar=gcr.io/foo/bar:v1\n",
+		},
+		{
+			description: "unknown format",
+			format:      "xml",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			var out bytes.Buffer
+			err := printBuildResult(&out, test.format, artifacts)
+			t.CheckError(test.shouldErr, err)
+			if !test.shouldErr {
+				t.CheckDeepEqual(test.expected, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/runner"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var buildArtifactsFile string
+
+// NewCmdDeploy describes the CLI command to deploy artifacts.
+func NewCmdDeploy(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploys the already built artifacts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeploy(out, filename)
+		},
+	}
+	AddRunDevFlags(cmd)
+	AddWaitFlags(cmd)
+	cmd.Flags().StringVarP(&buildArtifactsFile, "build-artifacts", "a", "", "File containing build artifacts, as produced by 'skaffold build --output json', to deploy")
+	return cmd
+}
+
+func runDeploy(out io.Writer, filename string) error {
+	ctx := context.Background()
+
+	config, err := readConfiguration(filename)
+	if err != nil {
+		return errors.Wrap(err, "reading configuration")
+	}
+
+	runner, err := runner.NewForConfig(opts, config)
+	if err != nil {
+		return errors.Wrap(err, "creating runner")
+	}
+
+	var artifacts []build.Artifact
+	if buildArtifactsFile != "" {
+		artifacts, err = readBuildArtifacts(buildArtifactsFile)
+		if err != nil {
+			return errors.Wrap(err, "reading build artifacts")
+		}
+	}
+
+	if err := runner.Deploy(ctx, out, artifacts); err != nil {
+		return errors.Wrap(err, "deploy step")
+	}
+
+	if waitForDeployments {
+		cli := &kubectl.CLI{
+			Namespace:   opts.Namespace,
+			KubeContext: opts.KubeContext,
+		}
+		// runner.RunID() is the same ID the deploy step above stamped its
+		// resources with when runner.NewForConfig created it, so this
+		// invocation's status check only ever discovers what it just
+		// deployed.
+		if err := deploy.StatusCheck(ctx, out, cli, runner.RunID(), deployTimeout, failFast); err != nil {
+			return errors.Wrap(err, "waiting for resources to stabilize")
+		}
+	}
+
+	return nil
+}
+
+// readBuildArtifacts reads the JSON array of build.Artifact written by
+// `skaffold build --output json` (or `-o json`) from path, so `skaffold
+// deploy` can tag the manifests it applies with the images a previous build
+// step actually produced instead of whatever tag policy the config computes
+// on its own.
+func readBuildArtifacts(path string) ([]build.Artifact, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+
+	var artifacts []build.Artifact
+	if err := json.Unmarshal(buf, &artifacts); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestReadBuildArtifacts(t *testing.T) {
+	var tests = []struct {
+		description string
+		contents    string
+		missing     bool
+		expected    []build.Artifact
+		shouldErr   bool
+	}{
+		{
+			description: "valid build output",
+			contents:    `[{"imageName":"gcr.io/foo/bar","tag":"gcr.io/foo/bar:v1","digest":"sha256:abc"}]`,
+			expected:    []build.Artifact{{ImageName: "gcr.io/foo/bar", Tag: "gcr.io/foo/bar:v1", Digest: "sha256:abc"}},
+		},
+		{
+			description: "empty array",
+			contents:    `[]`,
+			expected:    []build.Artifact{},
+		},
+		{
+			description: "invalid json",
+			contents:    `not json`,
+			shouldErr:   true,
+		},
+		{
+			description: "missing file",
+			missing:     true,
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			dir, err := ioutil.TempDir("", "skaffold-deploy-test")
+			if err != nil {
+				t.Fatalf("creating temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "build-artifacts.json")
+			if !test.missing {
+				if err := ioutil.WriteFile(path, []byte(test.contents), 0644); err != nil {
+					t.Fatalf("writing build artifacts file: %v", err)
+				}
+			}
+
+			actual, err := readBuildArtifacts(path)
+			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2018 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package build
+
+import "time"
+
+// Artifact is the result of building a single artifact, as returned by a
+// Builder's Build method. It carries everything downstream consumers
+// (deployers, GitOps writers, promotion scripts) need to reference the
+// image that was just built without re-deriving it.
+type Artifact struct {
+	ImageName string        `json:"imageName" yaml:"imageName"`
+	Tag       string        `json:"tag" yaml:"tag"`
+	ImageID   string        `json:"imageID,omitempty" yaml:"imageID,omitempty"`
+	Digest    string        `json:"digest,omitempty" yaml:"digest,omitempty"`
+	Builder   string        `json:"builder,omitempty" yaml:"builder,omitempty"`
+	Duration  time.Duration `json:"duration" yaml:"duration"`
+	CacheHit  bool          `json:"cacheHit" yaml:"cacheHit"`
+}
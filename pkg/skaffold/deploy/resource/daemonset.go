@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DaemonSet waits for a DaemonSet's rollout to finish.
+type DaemonSet struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewDaemonSet creates a DaemonSet resource that can be polled for readiness.
+func NewDaemonSet(client kubernetes.Interface, namespace, name string) *DaemonSet {
+	return &DaemonSet{client: client, namespace: namespace, name: name}
+}
+
+func (d *DaemonSet) Kind() string      { return "DaemonSet" }
+func (d *DaemonSet) Namespace() string { return d.namespace }
+func (d *DaemonSet) Name() string      { return d.name }
+
+func (d *DaemonSet) Status(ctx context.Context) (bool, error) {
+	ds, err := d.client.AppsV1().DaemonSets(d.namespace).Get(d.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting daemonset %s", d.name)
+	}
+
+	status := ds.Status
+	return status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+		status.NumberReady == status.DesiredNumberScheduled, nil
+}
+
+// TerminalError reports a crash-looping pod backing the DaemonSet, which a
+// rollout never recovers from on its own.
+func (d *DaemonSet) TerminalError(ctx context.Context) error {
+	ds, err := d.client.AppsV1().DaemonSets(d.namespace).Get(d.name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return crashLoopingPod(d.client, d.namespace, ds.Spec.Selector)
+}
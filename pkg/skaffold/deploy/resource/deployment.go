@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Deployment waits for a Deployment's rollout to finish.
+type Deployment struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewDeployment creates a Deployment resource that can be polled for readiness.
+func NewDeployment(client kubernetes.Interface, namespace, name string) *Deployment {
+	return &Deployment{client: client, namespace: namespace, name: name}
+}
+
+func (d *Deployment) Kind() string      { return "Deployment" }
+func (d *Deployment) Namespace() string { return d.namespace }
+func (d *Deployment) Name() string      { return d.name }
+
+// Status reports ready once the latest generation has been observed and every
+// replica has been updated, is available and there are no stragglers left
+// unavailable, mirroring what `kubectl rollout status` checks for Deployments.
+func (d *Deployment) Status(ctx context.Context) (bool, error) {
+	dep, err := d.client.AppsV1().Deployments(d.namespace).Get(d.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting deployment %s", d.name)
+	}
+
+	status := dep.Status
+	if status.ObservedGeneration < dep.Generation {
+		return false, nil
+	}
+
+	replicas := *dep.Spec.Replicas
+	return status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas &&
+		status.AvailableReplicas == replicas &&
+		status.UnavailableReplicas == 0, nil
+}
+
+// TerminalError always returns nil: Deployments are polled through
+// pollDeploymentsStatus/`kubectl rollout status` instead of this type, which
+// has its own terminal-error detection (see isTerminalError).
+func (d *Deployment) TerminalError(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Job waits for a Job to finish all its completions.
+type Job struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewJob creates a Job resource that can be polled for readiness.
+func NewJob(client kubernetes.Interface, namespace, name string) *Job {
+	return &Job{client: client, namespace: namespace, name: name}
+}
+
+func (j *Job) Kind() string      { return "Job" }
+func (j *Job) Namespace() string { return j.namespace }
+func (j *Job) Name() string      { return j.name }
+
+func (j *Job) Status(ctx context.Context) (bool, error) {
+	job, err := j.client.BatchV1().Jobs(j.namespace).Get(j.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting job %s", j.name)
+	}
+
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	return job.Status.Succeeded >= completions, nil
+}
+
+// TerminalError reports the Job's Failed condition, set once it's exhausted
+// its backoffLimit and will never complete on its own.
+func (j *Job) TerminalError(ctx context.Context) error {
+	job, err := j.client.BatchV1().Jobs(j.namespace).Get(j.name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return errors.Errorf("job %s failed: %s", j.name, c.Reason)
+		}
+	}
+	return nil
+}
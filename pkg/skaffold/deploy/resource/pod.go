@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Pod waits for a Pod to report Ready.
+type Pod struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewPod creates a Pod resource that can be polled for readiness.
+func NewPod(client kubernetes.Interface, namespace, name string) *Pod {
+	return &Pod{client: client, namespace: namespace, name: name}
+}
+
+func (p *Pod) Kind() string      { return "Pod" }
+func (p *Pod) Namespace() string { return p.namespace }
+func (p *Pod) Name() string      { return p.name }
+
+func (p *Pod) Status(ctx context.Context) (bool, error) {
+	pod, err := p.client.CoreV1().Pods(p.namespace).Get(p.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting pod %s", p.name)
+	}
+
+	return isPodReady(pod), nil
+}
+
+// TerminalError reports a container stuck in CrashLoopBackOff or an image
+// pull failure, which a Pod never recovers from on its own.
+func (p *Pod) TerminalError(ctx context.Context) error {
+	pod, err := p.client.CoreV1().Pods(p.namespace).Get(p.name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting == nil {
+			continue
+		}
+		switch cs.State.Waiting.Reason {
+		case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull":
+			return errors.Errorf("container %q: %s", cs.Name, cs.State.Waiting.Reason)
+		}
+	}
+	return nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// crashLoopingPod returns an error describing the first pod matching
+// selector whose container is stuck in CrashLoopBackOff or an image pull
+// failure, the kinds of failure a rolling update never recovers from on its
+// own. It returns nil if every matching pod is still progressing normally,
+// or if selector is missing or invalid or the pods can't be listed; those
+// errors are not reported.
+func crashLoopingPod(client kubernetes.Interface, namespace string, selector *metav1.LabelSelector) error {
+	if selector == nil {
+		return nil
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return nil
+	}
+
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting == nil {
+				continue
+			}
+			switch cs.State.Waiting.Reason {
+			case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull":
+				return fmt.Errorf("pod %s: container %q %s", pod.Name, cs.Name, cs.State.Waiting.Reason)
+			}
+		}
+	}
+
+	return nil
+}
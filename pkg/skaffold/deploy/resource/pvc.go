@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PersistentVolumeClaim waits for a PVC to be bound to a volume.
+type PersistentVolumeClaim struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewPersistentVolumeClaim creates a PVC resource that can be polled for readiness.
+func NewPersistentVolumeClaim(client kubernetes.Interface, namespace, name string) *PersistentVolumeClaim {
+	return &PersistentVolumeClaim{client: client, namespace: namespace, name: name}
+}
+
+func (p *PersistentVolumeClaim) Kind() string      { return "PersistentVolumeClaim" }
+func (p *PersistentVolumeClaim) Namespace() string { return p.namespace }
+func (p *PersistentVolumeClaim) Name() string      { return p.name }
+
+func (p *PersistentVolumeClaim) Status(ctx context.Context) (bool, error) {
+	pvc, err := p.client.CoreV1().PersistentVolumeClaims(p.namespace).Get(p.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting persistentvolumeclaim %s", p.name)
+	}
+
+	return pvc.Status.Phase == corev1.ClaimBound, nil
+}
+
+// TerminalError reports a PVC that has lost its bound volume, which it
+// never recovers from on its own.
+func (p *PersistentVolumeClaim) TerminalError(ctx context.Context) error {
+	pvc, err := p.client.CoreV1().PersistentVolumeClaims(p.namespace).Get(p.name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	if pvc.Status.Phase == corev1.ClaimLost {
+		return errors.Errorf("persistentvolumeclaim %s lost its bound volume", p.name)
+	}
+	return nil
+}
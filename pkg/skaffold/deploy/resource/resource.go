@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import "context"
+
+// Resource is a Kubernetes object that Skaffold deployed and that should be
+// waited on until it reaches a ready state. Each supported kind (Deployments,
+// StatefulSets, DaemonSets, Pods, Jobs, Services and PVCs) has its own
+// implementation with a kind-appropriate readiness predicate; other kinds are
+// not waited on.
+type Resource interface {
+	Kind() string
+	Namespace() string
+	Name() string
+
+	// Status checks the current state of the resource against the API server
+	// and reports whether it has become ready. A non-nil error is assumed to
+	// be a transient API server failure: callers retry it a bounded number of
+	// times before treating it as terminal.
+	Status(ctx context.Context) (done bool, err error)
+
+	// TerminalError reports whether the resource has entered a state it will
+	// never recover from on its own, e.g. a crash-looping pod backing it or a
+	// failed Job, as opposed to "still not ready yet". Callers use this to
+	// fail fast instead of polling Status until the deadline elapses. A nil
+	// return means no terminal condition was observed this poll.
+	TerminalError(ctx context.Context) error
+}
+
+// String returns the "kind/namespace/name" identifier used to key results in
+// the status check aggregator.
+func String(r Resource) string {
+	return r.Kind() + "/" + r.Namespace() + "/" + r.Name()
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestDeploymentStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		dep         *appsv1.Deployment
+		expected    bool
+	}{
+		{
+			description: "deployment rolled out",
+			dep: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "dep1", Namespace: "test", Generation: 1},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 1,
+					Replicas:           2,
+					UpdatedReplicas:    2,
+					AvailableReplicas:  2,
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "deployment still rolling out",
+			dep: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "dep2", Namespace: "test", Generation: 1},
+				Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 1,
+					Replicas:           2,
+					UpdatedReplicas:    1,
+					AvailableReplicas:  1,
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.dep)
+			done, err := NewDeployment(client, test.dep.Namespace, test.dep.Name).Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func TestStatefulSetStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		set         *appsv1.StatefulSet
+		expected    bool
+	}{
+		{
+			description: "rolling update finished",
+			set: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "set1", Namespace: "test"},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:       int32Ptr(2),
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+				},
+				Status: appsv1.StatefulSetStatus{UpdatedReplicas: 2, ReadyReplicas: 2},
+			},
+			expected: true,
+		},
+		{
+			description: "rolling update still in progress",
+			set: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "set2", Namespace: "test"},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:       int32Ptr(2),
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+				},
+				Status: appsv1.StatefulSetStatus{UpdatedReplicas: 1, ReadyReplicas: 1},
+			},
+			expected: false,
+		},
+		{
+			description: "OnDelete strategy is always considered ready",
+			set: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "set3", Namespace: "test"},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:       int32Ptr(2),
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+				},
+				Status: appsv1.StatefulSetStatus{UpdatedReplicas: 0, ReadyReplicas: 0},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.set)
+			done, err := NewStatefulSet(client, test.set.Namespace, test.set.Name).Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func TestDaemonSetStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		ds          *appsv1.DaemonSet
+		expected    bool
+	}{
+		{
+			description: "every desired node updated and ready",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "ds1", Namespace: "test"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					UpdatedNumberScheduled: 3,
+					NumberReady:            3,
+				},
+			},
+			expected: true,
+		},
+		{
+			description: "rollout still in progress",
+			ds: &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "ds2", Namespace: "test"},
+				Status: appsv1.DaemonSetStatus{
+					DesiredNumberScheduled: 3,
+					UpdatedNumberScheduled: 2,
+					NumberReady:            2,
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.ds)
+			done, err := NewDaemonSet(client, test.ds.Namespace, test.ds.Name).Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func TestJobStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		job         *batchv1.Job
+		expected    bool
+	}{
+		{
+			description: "completions reached",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test"},
+				Spec:       batchv1.JobSpec{Completions: int32Ptr(3)},
+				Status:     batchv1.JobStatus{Succeeded: 3},
+			},
+			expected: true,
+		},
+		{
+			description: "still running",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job2", Namespace: "test"},
+				Spec:       batchv1.JobSpec{Completions: int32Ptr(3)},
+				Status:     batchv1.JobStatus{Succeeded: 1},
+			},
+			expected: false,
+		},
+		{
+			description: "unset completions defaults to 1",
+			job: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job3", Namespace: "test"},
+				Status:     batchv1.JobStatus{Succeeded: 1},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.job)
+			done, err := NewJob(client, test.job.Namespace, test.job.Name).Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func TestPodStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		conditions  []corev1.PodCondition
+		expected    bool
+	}{
+		{
+			description: "ready",
+			conditions:  []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			expected:    true,
+		},
+		{
+			description: "not ready",
+			conditions:  []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			expected:    false,
+		},
+		{
+			description: "no ready condition reported yet",
+			conditions:  nil,
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "test"},
+				Status:     corev1.PodStatus{Conditions: test.conditions},
+			}
+			client := fake.NewSimpleClientset(pod)
+			done, err := NewPod(client, "test", "pod1").Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func TestServiceStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		svc         *corev1.Service
+		expected    bool
+	}{
+		{
+			description: "ClusterIP service has an IP",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "test"},
+				Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.1"},
+			},
+			expected: true,
+		},
+		{
+			description: "ClusterIP not assigned yet",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc2", Namespace: "test"},
+			},
+			expected: false,
+		},
+		{
+			description: "LoadBalancer waiting for ingress",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc3", Namespace: "test"},
+				Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.2", Type: corev1.ServiceTypeLoadBalancer},
+			},
+			expected: false,
+		},
+		{
+			description: "LoadBalancer ingress provisioned",
+			svc: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc4", Namespace: "test"},
+				Spec:       corev1.ServiceSpec{ClusterIP: "10.0.0.3", Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+				},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			client := fake.NewSimpleClientset(test.svc)
+			done, err := NewService(client, test.svc.Namespace, test.svc.Name).Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func TestPersistentVolumeClaimStatus(t *testing.T) {
+	var tests = []struct {
+		description string
+		phase       corev1.PersistentVolumeClaimPhase
+		expected    bool
+	}{
+		{description: "bound", phase: corev1.ClaimBound, expected: true},
+		{description: "pending", phase: corev1.ClaimPending, expected: false},
+	}
+
+	for _, test := range tests {
+		testutil.Run(t, test.description, func(t *testutil.T) {
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc1", Namespace: "test"},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: test.phase},
+			}
+			client := fake.NewSimpleClientset(pvc)
+			done, err := NewPersistentVolumeClaim(client, "test", "pvc1").Status(context.Background())
+			t.CheckError(false, err)
+			t.CheckDeepEqual(test.expected, done)
+		})
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Service waits for a Service to be assigned an IP, and for LoadBalancer
+// Services, for the external ingress to be provisioned.
+type Service struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewService creates a Service resource that can be polled for readiness.
+func NewService(client kubernetes.Interface, namespace, name string) *Service {
+	return &Service{client: client, namespace: namespace, name: name}
+}
+
+func (s *Service) Kind() string      { return "Service" }
+func (s *Service) Namespace() string { return s.namespace }
+func (s *Service) Name() string      { return s.name }
+
+func (s *Service) Status(ctx context.Context) (bool, error) {
+	svc, err := s.client.CoreV1().Services(s.namespace).Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting service %s", s.name)
+	}
+
+	if svc.Spec.ClusterIP == "" && svc.Spec.Type != corev1.ServiceTypeExternalName {
+		return false, nil
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		return len(svc.Status.LoadBalancer.Ingress) > 0, nil
+	}
+
+	return true, nil
+}
+
+// TerminalError always returns nil: a Service has no notion of a failure it
+// can't eventually recover from, it just waits for an IP to be assigned.
+func (s *Service) TerminalError(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StatefulSet waits for a StatefulSet's rolling update to finish.
+type StatefulSet struct {
+	namespace string
+	name      string
+	client    kubernetes.Interface
+}
+
+// NewStatefulSet creates a StatefulSet resource that can be polled for readiness.
+func NewStatefulSet(client kubernetes.Interface, namespace, name string) *StatefulSet {
+	return &StatefulSet{client: client, namespace: namespace, name: name}
+}
+
+func (s *StatefulSet) Kind() string      { return "StatefulSet" }
+func (s *StatefulSet) Namespace() string { return s.namespace }
+func (s *StatefulSet) Name() string      { return s.name }
+
+// Status only understands the default RollingUpdate strategy: OnDelete
+// StatefulSets never converge on their own, so we treat them as ready as soon
+// as they're observed.
+func (s *StatefulSet) Status(ctx context.Context) (bool, error) {
+	set, err := s.client.AppsV1().StatefulSets(s.namespace).Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrapf(err, "getting statefulset %s", s.name)
+	}
+
+	if set.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return true, nil
+	}
+
+	replicas := *set.Spec.Replicas
+	return set.Status.UpdatedReplicas == replicas && set.Status.ReadyReplicas == replicas, nil
+}
+
+// TerminalError reports a crash-looping pod backing the StatefulSet, which
+// a rolling update never recovers from on its own.
+func (s *StatefulSet) TerminalError(ctx context.Context) error {
+	set, err := s.client.AppsV1().StatefulSets(s.namespace).Get(s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return crashLoopingPod(s.client, s.namespace, set.Spec.Selector)
+}
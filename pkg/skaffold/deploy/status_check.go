@@ -0,0 +1,565 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deploy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/resource"
+	kubernetesutil "github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const tabHeader = " -"
+
+// kubernetesMaxRetries is how many consecutive API server errors a resource
+// poll tolerates before giving up and treating the error as terminal.
+const kubernetesMaxRetries = 3
+
+var defaultPollPeriodInMilliseconds = 200
+
+var executeRolloutStatus = getRollOutStatus
+
+// NewRunID generates a short, random identifier for one `skaffold
+// build --wait`/`skaffold deploy` invocation. It's meant to be called once,
+// by runner.NewForConfig, and held for the lifetime of that Runner: the
+// deploy phase stamps every resource it applies with the matching
+// `app.kubernetes.io/managed-by=skaffold-<runID>` label, and callers pass the
+// same Runner-held ID into StatusCheck so discovery only ever matches what
+// this run deployed. Generating a fresh ID at the StatusCheck call site
+// instead of reusing the one the deploy phase stamped resources with would
+// make the selector match nothing.
+func NewRunID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// resourceAggregator is a typed, concurrency-safe collector of per-resource
+// status check outcomes, keyed by resource name (or resource.String() for
+// the generalized kinds). It replaces the untyped sync.Map that
+// getDeployStatus used to read from directly.
+type resourceAggregator struct {
+	results  sync.Map
+	messages sync.Map
+}
+
+func newResourceAggregator() *resourceAggregator {
+	return &resourceAggregator{}
+}
+
+func (a *resourceAggregator) store(key string, err error) {
+	if err != nil {
+		a.results.Store(key, err)
+		return
+	}
+	a.results.Store(key, "SUCCESS")
+}
+
+// storeMessage records the last observed status message for key (e.g. a
+// `kubectl rollout status` line, or "ready"/"could not stabilize within
+// deadline" for the generalized kinds), independently of whether key ends up
+// ready or failed. It feeds the per-resource summary StatusCheck prints once
+// every poll has finished.
+func (a *resourceAggregator) storeMessage(key, message string) {
+	a.messages.Store(key, message)
+}
+
+func (a *resourceAggregator) errors() []error {
+	var errs []error
+	a.results.Range(func(key, value interface{}) bool {
+		if err, ok := value.(error); ok {
+			errs = append(errs, fmt.Errorf("%s failed due to %s", key, err))
+		}
+		return true
+	})
+	return errs
+}
+
+// resourceSummary is one line of the per-resource report StatusCheck prints
+// once every poll has settled: whether the resource became ready, and the
+// last status message observed for it.
+type resourceSummary struct {
+	name    string
+	ready   bool
+	message string
+}
+
+// summary returns one resourceSummary per resource stored in a, sorted by
+// name for deterministic output.
+func (a *resourceAggregator) summary() []resourceSummary {
+	var summary []resourceSummary
+	a.results.Range(func(key, value interface{}) bool {
+		name := key.(string)
+		_, failed := value.(error)
+		message, _ := a.messages.Load(name)
+		msg, _ := message.(string)
+		summary = append(summary, resourceSummary{name: name, ready: !failed, message: msg})
+		return true
+	})
+	sort.Slice(summary, func(i, j int) bool { return summary[i].name < summary[j].name })
+	return summary
+}
+
+// printStatusSummary writes one line per resource StatusCheck waited on,
+// noting whether it became ready and the last status message observed for
+// it, so a `--wait` run leaves a trail of what happened to every resource
+// instead of only a pass/fail error.
+func printStatusSummary(out io.Writer, aggregator *resourceAggregator) {
+	summary := aggregator.summary()
+	if len(summary) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "Status check summary:")
+	for _, s := range summary {
+		state := "timed out"
+		if s.ready {
+			state = "ready"
+		}
+		if s.message == "" {
+			fmt.Fprintf(out, "%s %s: %s\n", tabHeader, s.name, state)
+			continue
+		}
+		fmt.Fprintf(out, "%s %s: %s (%s)\n", tabHeader, s.name, state, s.message)
+	}
+}
+
+// StatusCheck waits for every resource that `kubectl apply` deployed for this
+// run to reach a ready state, or for deadline to elapse, whichever happens
+// first. Deployments are polled through `kubectl rollout status`, which
+// already understands progressDeadlineSeconds; every other kind is polled
+// directly against the API server through the resource.Resource interface.
+//
+// When failFast is set, any resource returning a terminal error (for a
+// Deployment: an ImagePullBackOff, a "could not be found", or a
+// CrashLoopBackOff pod under its selector; for every other kind, whatever
+// its resource.Resource.TerminalError reports) cancels every other
+// in-flight poll instead of letting them run to their own deadline.
+//
+// runID scopes discovery to the resources this invocation deployed; see
+// NewRunID.
+//
+// Once every resource has either stabilized or timed out, StatusCheck writes
+// a summary to out noting which became ready, which timed out, and the last
+// status message observed for each, before returning the aggregated error
+// (if any).
+func StatusCheck(ctx context.Context, out io.Writer, cli *kubectl.CLI, runID string, deadline time.Duration, failFast bool) error {
+	client, err := kubernetesutil.GetClientset()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+
+	selector := fmt.Sprintf("app.kubernetes.io/managed-by=skaffold-%s", runID)
+	resources, deployments, err := discoverResources(client, cli.Namespace, selector)
+	if err != nil {
+		return errors.Wrap(err, "discovering deployed resources")
+	}
+
+	deadlines, err := getDeadlineForDeployments(ctx, cli, runID)
+	if err != nil {
+		return errors.Wrap(err, "getting deployment deadlines")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	onTerminalErr := func() {}
+	if failFast {
+		onTerminalErr = cancel
+	}
+
+	aggregator := newResourceAggregator()
+	var wg sync.WaitGroup
+
+	for _, name := range deployments {
+		depDeadline := deadline
+		if d, ok := deadlines[name]; ok && time.Duration(d)*time.Second < deadline {
+			depDeadline = time.Duration(d) * time.Second
+		}
+
+		wg.Add(1)
+		go func(name string, depDeadline time.Duration) {
+			defer wg.Done()
+			pollDeploymentsStatus(ctx, cli, client, name, depDeadline, aggregator, onTerminalErr)
+		}(name, depDeadline)
+	}
+
+	for _, r := range resources {
+		wg.Add(1)
+		go func(r resource.Resource) {
+			defer wg.Done()
+			pollResourceStatus(ctx, r, deadline, aggregator, onTerminalErr)
+		}(r)
+	}
+
+	wg.Wait()
+	printStatusSummary(out, aggregator)
+	return getDeployStatus(aggregator)
+}
+
+// discoverResources lists every resource kind Skaffold knows how to wait on
+// that matches selector, returning the generic resource.Resource wrappers
+// alongside the plain Deployment names (Deployments keep using
+// pollDeploymentsStatus/kubectl rollout status).
+func discoverResources(client kubernetes.Interface, namespace, selector string) ([]resource.Resource, []string, error) {
+	opts := metav1.ListOptions{LabelSelector: selector}
+
+	var resources []resource.Resource
+	var deployments []string
+
+	deps, err := client.AppsV1().Deployments(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing deployments")
+	}
+	for _, d := range deps.Items {
+		deployments = append(deployments, d.Name)
+	}
+
+	sets, err := client.AppsV1().StatefulSets(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing statefulsets")
+	}
+	for _, s := range sets.Items {
+		resources = append(resources, resource.NewStatefulSet(client, namespace, s.Name))
+	}
+
+	daemons, err := client.AppsV1().DaemonSets(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing daemonsets")
+	}
+	for _, d := range daemons.Items {
+		resources = append(resources, resource.NewDaemonSet(client, namespace, d.Name))
+	}
+
+	jobs, err := client.BatchV1().Jobs(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing jobs")
+	}
+	for _, j := range jobs.Items {
+		resources = append(resources, resource.NewJob(client, namespace, j.Name))
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing pods")
+	}
+	for _, p := range pods.Items {
+		resources = append(resources, resource.NewPod(client, namespace, p.Name))
+	}
+
+	svcs, err := client.CoreV1().Services(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing services")
+	}
+	for _, s := range svcs.Items {
+		resources = append(resources, resource.NewService(client, namespace, s.Name))
+	}
+
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "listing persistentvolumeclaims")
+	}
+	for _, p := range pvcs.Items {
+		resources = append(resources, resource.NewPersistentVolumeClaim(client, namespace, p.Name))
+	}
+
+	return resources, deployments, nil
+}
+
+// pollResourceStatus polls a generic resource.Resource until it reports done,
+// the deadline elapses, the API server returns more than kubernetesMaxRetries
+// consecutive errors (at which point the last error is treated as terminal),
+// or r.TerminalError reports a failure it will never recover from on its
+// own. onTerminalErr is invoked whenever either of the latter two happen, so
+// a --fail-fast supervisor can cancel every other in-flight poll the same
+// way it does for Deployments (see StatusCheck).
+func pollResourceStatus(ctx context.Context, r resource.Resource, deadline time.Duration, aggregator *resourceAggregator, onTerminalErr func()) {
+	key := resource.String(r)
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	var consecutiveErrs int
+	for {
+		done, err := r.Status(ctx)
+		switch {
+		case err != nil:
+			consecutiveErrs++
+			if consecutiveErrs > kubernetesMaxRetries {
+				aggregator.storeMessage(key, err.Error())
+				aggregator.store(key, err)
+				onTerminalErr()
+				return
+			}
+		case done:
+			aggregator.storeMessage(key, "ready")
+			aggregator.store(key, nil)
+			return
+		default:
+			consecutiveErrs = 0
+			if termErr := r.TerminalError(ctx); termErr != nil {
+				aggregator.storeMessage(key, termErr.Error())
+				aggregator.store(key, termErr)
+				onTerminalErr()
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			aggregator.storeMessage(key, "could not stabilize within deadline")
+			aggregator.store(key, errors.New("could not stabilize within deadline"))
+			return
+		case <-time.After(time.Duration(defaultPollPeriodInMilliseconds) * time.Millisecond):
+		}
+	}
+}
+
+// getDeadlineForDeployments gets the spec.progressDeadlineSeconds for every
+// deployment matching runID's label selector.
+func getDeadlineForDeployments(ctx context.Context, cli *kubectl.CLI, runID string) (map[string]float32, error) {
+	const template = `{{range .items}}{{.metadata.name}}:{{.spec.progressDeadlineSeconds}},{{end}}`
+
+	deps := map[string]float32{}
+	output, err := util.RunCmdOut(cli.Command(ctx,
+		"get", "deployments",
+		"-l", fmt.Sprintf("app.kubernetes.io/managed-by=skaffold-%s", runID),
+		"--output", fmt.Sprintf("go-template='%s'", template),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "getting deployments")
+	}
+
+	trimmed := strings.Trim(strings.TrimSpace(string(output)), "'")
+	if trimmed == "" {
+		return deps, nil
+	}
+
+	for _, entry := range strings.Split(trimmed, ",") {
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 2)
+		var seconds float32
+		fmt.Sscanf(parts[1], "%f", &seconds)
+		deps[parts[0]] = seconds
+	}
+
+	return deps, nil
+}
+
+// pollDeploymentsStatus runs `kubectl rollout status` for name until it
+// succeeds, the duration elapses, ctx is cancelled by a peer's terminal
+// failure, or the command itself returns an error. Results are
+// written into aggregator under name rather than returned directly, so many
+// deployments can be polled concurrently and collected once they're all
+// done. client may be nil, in which case a timeout is reported with the
+// last rollout message only, without pod-level detail. onTerminalErr is
+// invoked whenever this poll stores a terminal error, so a supervisor can
+// cancel ctx and abort every other in-flight poll (see StatusCheck).
+func pollDeploymentsStatus(ctx context.Context, cli *kubectl.CLI, client kubernetes.Interface, name string, duration time.Duration, aggregator *resourceAggregator, onTerminalErr func()) {
+	pollDuration := time.Duration(defaultPollPeriodInMilliseconds) * time.Millisecond
+	ticker := time.NewTicker(pollDuration)
+	defer ticker.Stop()
+
+	var lastStatus string
+	deadline := time.After(duration)
+	for {
+		select {
+		case <-ctx.Done():
+			aggregator.storeMessage(name, lastStatus)
+			aggregator.store(name, errors.New("rollout cancelled: another resource failed"))
+			return
+		case <-deadline:
+			err := deploymentTimeoutError(client, cli.Namespace, name, lastStatus)
+			aggregator.storeMessage(name, lastStatus)
+			aggregator.store(name, err)
+			if isTerminalError(err) {
+				onTerminalErr()
+			}
+			return
+		case <-ticker.C:
+			status, err := executeRolloutStatus(ctx, cli, name)
+			if err != nil {
+				aggregator.storeMessage(name, err.Error())
+				aggregator.store(name, err)
+				if isTerminalError(err) {
+					onTerminalErr()
+				}
+				return
+			}
+			if strings.Contains(status, "successfully rolled out") {
+				aggregator.storeMessage(name, status)
+				aggregator.store(name, nil)
+				return
+			}
+			lastStatus = status
+		}
+	}
+}
+
+// isTerminalError reports whether err indicates a rollout that will never
+// recover on its own: an unschedulable image pull, a missing Deployment, or
+// a crash-looping pod.
+func isTerminalError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, substr := range []string{"ImagePullBackOff", "could not be found", "CrashLoopBackOff"} {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func getRollOutStatus(ctx context.Context, cli *kubectl.CLI, name string) (string, error) {
+	output, err := util.RunCmdOut(cli.Command(ctx, "rollout", "status", "deployment", name, "--watch=false"))
+	return string(output), err
+}
+
+// deploymentTimeoutError builds an actionable error for a Deployment that
+// didn't stabilize in time: it surfaces the configured maxUnavailable and,
+// for every pod that isn't Ready, its last termination reason and most
+// recent Warning event, e.g.
+//
+//	deployment dep2 failed: 1/3 pods CrashLoopBackOff (container "web": exit 137 OOMKilled), maxUnavailable=1
+//
+// If the pod-level detail can't be fetched (client is nil, or the API call
+// fails), it falls back to the last rollout status message.
+func deploymentTimeoutError(client kubernetes.Interface, namespace, name, lastStatus string) error {
+	fallback := errors.New("could not return within default timeout")
+	if lastStatus != "" {
+		fallback = errors.Errorf("could not return within default timeout: %s", strings.TrimSpace(lastStatus))
+	}
+	if client == nil {
+		return fallback
+	}
+
+	dep, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return fallback
+	}
+
+	maxUnavailable := "25%"
+	if ru := dep.Spec.Strategy.RollingUpdate; ru != nil && ru.MaxUnavailable != nil {
+		maxUnavailable = ru.MaxUnavailable.String()
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(dep.Spec.Selector)
+	if err != nil {
+		return fallback
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return fallback
+	}
+
+	var notReady []string
+	for _, pod := range pods.Items {
+		if !podReady(pod) {
+			notReady = append(notReady, podFailureReason(client, pod))
+		}
+	}
+	if len(notReady) == 0 {
+		return fmt.Errorf("deployment %s failed: %s, maxUnavailable=%s", name, lastStatus, maxUnavailable)
+	}
+
+	return fmt.Errorf("deployment %s failed: %d/%d pods %s, maxUnavailable=%s",
+		name, len(notReady), len(pods.Items), strings.Join(notReady, "; "), maxUnavailable)
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podFailureReason describes why pod isn't ready: the waiting reason of the
+// last waiting container seen up to and including the first container that
+// has terminated before (whose exit code and termination reason are added),
+// plus the most recent Warning event on the pod, if any.
+func podFailureReason(client kubernetes.Interface, pod corev1.Pod) string {
+	reason := "not ready"
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil {
+			reason = cs.State.Waiting.Reason
+		}
+		if term := cs.LastTerminationState.Terminated; term != nil {
+			reason = fmt.Sprintf("%s (container %q: exit %d %s)", reason, cs.Name, term.ExitCode, term.Reason)
+			break
+		}
+	}
+
+	if event := lastWarningEvent(client, pod); event != "" {
+		reason = fmt.Sprintf("%s - %s", reason, event)
+	}
+
+	return reason
+}
+
+func lastWarningEvent(client kubernetes.Interface, pod corev1.Pod) string {
+	events, err := client.CoreV1().Events(pod.Namespace).List(metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,type=Warning", pod.Name),
+	})
+	if err != nil || len(events.Items) == 0 {
+		return ""
+	}
+
+	latest := events.Items[0]
+	for _, e := range events.Items[1:] {
+		if e.LastTimestamp.After(latest.LastTimestamp.Time) {
+			latest = e
+		}
+	}
+	return latest.Message
+}
+
+// getDeployStatus aggregates every result stored in aggregator into a single
+// error, one line per resource that failed to stabilize.
+func getDeployStatus(aggregator *resourceAggregator) error {
+	errs := aggregator.errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return fmt.Errorf("following resources failed to stabilize:\n%s %s", tabHeader, strings.Join(messages, fmt.Sprintf("\n%s ", tabHeader)))
+}
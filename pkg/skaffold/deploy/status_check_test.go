@@ -17,6 +17,7 @@ limitations under the License.
 package deploy
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -24,7 +25,15 @@ import (
 	"testing"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/kubectl"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/deploy/resource"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
 	"github.com/GoogleContainerTools/skaffold/testutil"
 )
@@ -65,7 +74,7 @@ func TestGetDeadlineForDeployments(t *testing.T) {
 				Namespace:   "test",
 				KubeContext: testKubeContext,
 			}
-			actual, err := getDeadlineForDeployments(context.Background(), cli)
+			actual, err := getDeadlineForDeployments(context.Background(), cli, "unknown")
 			t.CheckErrorAndDeepEqual(test.shouldErr, err, test.expected, actual)
 		})
 	}
@@ -95,11 +104,13 @@ func (m *MockRolloutStatus) Executefunc(context.Context, *kubectl.CLI, string) (
 func TestPollDeploymentsStatus(t *testing.T) {
 
 	var tests = []struct {
-		description string
-		mock        *MockRolloutStatus
-		duration    int
-		exactCalls  int
-		shouldErr   bool
+		description    string
+		mock           *MockRolloutStatus
+		client         kubernetes.Interface
+		duration       int
+		exactCalls     int
+		shouldErr      bool
+		expectedErrMsg string
 	}{
 		{
 			description: "rollout returns success",
@@ -140,6 +151,20 @@ func TestPollDeploymentsStatus(t *testing.T) {
 			duration:  1000,
 			shouldErr: true,
 		},
+		{
+			description: "timeout reports a crashlooping pod's failure reason",
+			mock: &MockRolloutStatus{
+				responses: []string{
+					"Waiting for rollout to finish: 1 of 3 updated replicas are available...",
+					"Waiting for rollout to finish: 1 of 3 updated replicas are available...",
+					"Waiting for rollout to finish: 1 of 3 updated replicas are available...",
+				},
+			},
+			client:         fakeDeploymentClientWithCrashingPod(t),
+			duration:       1000,
+			shouldErr:      true,
+			expectedErrMsg: `1/1 pods CrashLoopBackOff (container "web": exit 137 OOMKilled), maxUnavailable=1`,
+		},
 	}
 	originalPollingPeriod := defaultPollPeriodInMilliseconds
 	for _, test := range tests {
@@ -153,10 +178,10 @@ func TestPollDeploymentsStatus(t *testing.T) {
 			defaultPollPeriodInMilliseconds = 100
 			defer func() { defaultPollPeriodInMilliseconds = originalPollingPeriod }()
 
-			actual := &sync.Map{}
-			pollDeploymentsStatus(context.Background(), &kubectl.CLI{}, "dep", time.Duration(test.duration)*time.Millisecond, actual)
+			actual := newResourceAggregator()
+			pollDeploymentsStatus(context.Background(), &kubectl.CLI{}, test.client, "dep", time.Duration(test.duration)*time.Millisecond, actual, func() {})
 
-			if _, ok := actual.Load("dep"); !ok {
+			if _, ok := actual.results.Load("dep"); !ok {
 				t.Error("expected result for deployment dep. But found none")
 			}
 			err := getDeployStatus(actual)
@@ -164,10 +189,181 @@ func TestPollDeploymentsStatus(t *testing.T) {
 			if test.exactCalls > 0 {
 				t.CheckDeepEqual(test.exactCalls, mock.called)
 			}
+			if test.expectedErrMsg != "" {
+				t.CheckErrorContains(test.expectedErrMsg, err)
+			}
 		})
 	}
 }
 
+// TestPollDeploymentsStatusFailFast asserts that when one deployment's
+// rollout returns a terminal error, cancelling the shared context (as
+// StatusCheck does when --fail-fast is set) makes a peer still waiting on
+// its own rollout stop within one poll interval instead of running to its
+// full duration.
+func TestPollDeploymentsStatusFailFast(t *testing.T) {
+	originalPollingPeriod := defaultPollPeriodInMilliseconds
+	defaultPollPeriodInMilliseconds = 50
+	defer func() { defaultPollPeriodInMilliseconds = originalPollingPeriod }()
+
+	failing := &MockRolloutStatus{err: errors.New("deployment.apps/dep1 could not be found")}
+	stuck := &MockRolloutStatus{
+		responses: []string{"Waiting for rollout to finish: 0 of 1 updated replicas are available..."},
+	}
+
+	executeRolloutStatus = func(ctx context.Context, cli *kubectl.CLI, name string) (string, error) {
+		if name == "dep1" {
+			return failing.Executefunc(ctx, cli, name)
+		}
+		return stuck.Executefunc(ctx, cli, name)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	failingAggregator := newResourceAggregator()
+	stuckAggregator := newResourceAggregator()
+
+	var cancelledAt, stuckStoppedAt time.Time
+	onTerminalErr := func() {
+		cancelledAt = time.Now()
+		cancel()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		pollDeploymentsStatus(ctx, &kubectl.CLI{}, nil, "dep1", 10*time.Second, failingAggregator, onTerminalErr)
+	}()
+
+	go func() {
+		defer wg.Done()
+		pollDeploymentsStatus(ctx, &kubectl.CLI{}, nil, "dep2", 10*time.Second, stuckAggregator, func() {})
+		stuckStoppedAt = time.Now()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("peer poll did not observe cancellation within the expected window")
+	}
+	executeRolloutStatus = getRollOutStatus
+
+	if elapsed := stuckStoppedAt.Sub(cancelledAt); elapsed > time.Duration(defaultPollPeriodInMilliseconds)*time.Millisecond {
+		t.Errorf("peer poll took %v after cancellation to stop, want at most one poll interval", elapsed)
+	}
+	if _, ok := stuckAggregator.results.Load("dep2"); !ok {
+		t.Error("expected a cancellation result for dep2, found none")
+	}
+}
+
+// fakeResource is a minimal resource.Resource used to test
+// pollResourceStatus's fail-fast wiring without a real Kubernetes client.
+type fakeResource struct {
+	name        string
+	terminalErr error
+}
+
+func (f *fakeResource) Kind() string                         { return "StatefulSet" }
+func (f *fakeResource) Namespace() string                    { return "test" }
+func (f *fakeResource) Name() string                         { return f.name }
+func (f *fakeResource) Status(context.Context) (bool, error) { return false, nil }
+func (f *fakeResource) TerminalError(context.Context) error  { return f.terminalErr }
+
+// TestPollResourceStatusFailFast asserts that a generalized resource (e.g. a
+// StatefulSet with a crash-looping pod) cancelling the shared context trips
+// --fail-fast for its peers the same way a failing Deployment does.
+func TestPollResourceStatusFailFast(t *testing.T) {
+	originalPollingPeriod := defaultPollPeriodInMilliseconds
+	defaultPollPeriodInMilliseconds = 50
+	defer func() { defaultPollPeriodInMilliseconds = originalPollingPeriod }()
+
+	failing := &fakeResource{name: "set1", terminalErr: errors.New(`pod set1-0: container "web" CrashLoopBackOff`)}
+	stuck := &fakeResource{name: "set2"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	failingAggregator := newResourceAggregator()
+	stuckAggregator := newResourceAggregator()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		pollResourceStatus(ctx, failing, 10*time.Second, failingAggregator, cancel)
+	}()
+
+	go func() {
+		defer wg.Done()
+		pollResourceStatus(ctx, stuck, 10*time.Second, stuckAggregator, func() {})
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("peer poll did not observe cancellation within the expected window")
+	}
+
+	if _, ok := stuckAggregator.results.Load(resource.String(stuck)); !ok {
+		t.Error("expected a cancellation result for set2, found none")
+	}
+}
+
+// fakeDeploymentClientWithCrashingPod returns a fake clientset seeded with a
+// Deployment "dep" whose single pod is crash-looping, for the
+// deploymentTimeoutError pod-detail path.
+func fakeDeploymentClientWithCrashingPod(t *testing.T) kubernetes.Interface {
+	one := int32(1)
+	maxUnavailable := intstr.FromInt(1)
+
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "dep", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &one,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "dep"}},
+			Strategy: appsv1.DeploymentStrategy{
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &maxUnavailable},
+			},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "dep-abc", Namespace: "default", Labels: map[string]string{"app": "dep"}},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "web",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+					},
+				},
+			},
+		},
+	}
+
+	return fake.NewSimpleClientset(dep, pod)
+}
+
 func TestGetDeployStatus(t *testing.T) {
 	var tests = []struct {
 		description    string
@@ -181,7 +377,7 @@ func TestGetDeployStatus(t *testing.T) {
 				"dep1": "SUCCESS",
 				"dep2": fmt.Errorf("could not return within default timeout"),
 			},
-			expectedErrMsg: []string{"deployment dep2 failed due to could not return within default timeout"},
+			expectedErrMsg: []string{"dep2 failed due to could not return within default timeout"},
 			shouldErr:      true,
 		},
 		{
@@ -198,19 +394,19 @@ func TestGetDeployStatus(t *testing.T) {
 				"dep2": fmt.Errorf("could not return within default timeout"),
 				"dep3": fmt.Errorf("ERROR"),
 			},
-			expectedErrMsg: []string{"deployment dep2 failed due to could not return within default timeout",
-				"deployment dep3 failed due to ERROR"},
+			expectedErrMsg: []string{"dep2 failed due to could not return within default timeout",
+				"dep3 failed due to ERROR"},
 			shouldErr: true,
 		},
 	}
 
 	for _, test := range tests {
 		testutil.Run(t, test.description, func(t *testutil.T) {
-			syncMap := &sync.Map{}
+			aggregator := newResourceAggregator()
 			for k, v := range test.deps {
-				syncMap.Store(k, v)
+				aggregator.results.Store(k, v)
 			}
-			err := getDeployStatus(syncMap)
+			err := getDeployStatus(aggregator)
 			t.CheckError(test.shouldErr, err)
 			for _, msg := range test.expectedErrMsg {
 				t.CheckErrorContains(msg, err)
@@ -218,3 +414,21 @@ func TestGetDeployStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintStatusSummary(t *testing.T) {
+	testutil.Run(t, "ready and timed out resources", func(t *testutil.T) {
+		aggregator := newResourceAggregator()
+		aggregator.store("Deployment/test/dep1", nil)
+		aggregator.storeMessage("Deployment/test/dep1", "successfully rolled out")
+		aggregator.store("Pod/test/pod1", errors.New("could not stabilize within deadline"))
+		aggregator.storeMessage("Pod/test/pod1", "could not stabilize within deadline")
+
+		var out bytes.Buffer
+		printStatusSummary(&out, aggregator)
+
+		expected := "Status check summary:\n" +
+			" - Deployment/test/dep1: ready (successfully rolled out)\n" +
+			" - Pod/test/pod1: timed out (could not stabilize within deadline)\n"
+		t.CheckDeepEqual(expected, out.String())
+	})
+}